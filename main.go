@@ -9,16 +9,19 @@ import (
 	_ "image/jpeg"
 	_ "image/png"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Nyuuk/go-qr-qris/qr"
 	"github.com/gofiber/fiber/v2"
 	"github.com/joho/godotenv"
 	goqr "github.com/liyue201/goqr"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	qrcodeGen "github.com/skip2/go-qrcode"
+	"golang.org/x/sync/errgroup"
 )
 
 // computeCRC16CCITT computes CRC-16/CCITT-FALSE (poly 0x1021, init 0xFFFF, xorout 0x0000)
@@ -48,8 +51,41 @@ type emvTag struct {
 	value string
 }
 
-func parseEMV(s string) ([]emvTag, error) {
-	var tags []emvTag
+// emvNode is one EMV TLV entry. Children is populated when id names a QRIS
+// template tag (merchant account info 26-51, additional data 62, merchant
+// information language template 64, or unreserved templates 80-99), whose
+// value is itself a nested TLV string.
+type emvNode struct {
+	ID       string    `json:"id"`
+	Length   int       `json:"length"`
+	Value    string    `json:"value,omitempty"`
+	Children []emvNode `json:"children,omitempty"`
+}
+
+// isTemplateTag reports whether id's value is itself a nested EMV TLV
+// structure per the QRIS spec, rather than a plain value.
+func isTemplateTag(id string) bool {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return false
+	}
+	switch {
+	case n >= 26 && n <= 51:
+		return true
+	case id == "62" || id == "64":
+		return true
+	case n >= 80 && n <= 99:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseEMVTree parses s into a tree of EMV TLV nodes, recursing into
+// template tags so nested sub-tags (e.g. additional data field 62's bill
+// number and reference label) are exposed as children.
+func parseEMVTree(s string) ([]emvNode, error) {
+	var nodes []emvNode
 	i := 0
 	n := len(s)
 	for i < n {
@@ -59,19 +95,91 @@ func parseEMV(s string) ([]emvTag, error) {
 		id := s[i : i+2]
 		lenStr := s[i+2 : i+4]
 		l, err := strconv.Atoi(lenStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid length for tag %s: %v", id, err)
+		if err != nil || l < 0 {
+			return nil, fmt.Errorf("invalid length for tag %s: %q is not a 2-digit unsigned length", id, lenStr)
 		}
 		if i+4+l > n {
 			return nil, fmt.Errorf("malformed EMV data: tag %s length out of range", id)
 		}
 		val := s[i+4 : i+4+l]
-		tags = append(tags, emvTag{id: id, value: val})
+		node := emvNode{ID: id, Length: l, Value: val}
+		if isTemplateTag(id) {
+			children, err := parseEMVTree(val)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse sub-tags of tag %s: %w", id, err)
+			}
+			node.Children = children
+		}
+		nodes = append(nodes, node)
 		i = i + 4 + l
 	}
+	return nodes, nil
+}
+
+// findEMVNode returns the first top-level node with the given id.
+func findEMVNode(nodes []emvNode, id string) (emvNode, bool) {
+	for _, n := range nodes {
+		if n.ID == id {
+			return n, true
+		}
+	}
+	return emvNode{}, false
+}
+
+// emvValue returns the value of the first top-level node with the given
+// id, or "" if absent.
+func emvValue(nodes []emvNode, id string) string {
+	if n, ok := findEMVNode(nodes, id); ok {
+		return n.Value
+	}
+	return ""
+}
+
+// parseEMV parses s into a flat list of top-level EMV tags, leaving
+// template tag values unexpanded. It is a thin wrapper over parseEMVTree
+// for callers that only need the top level (e.g. amount-tag rewriting).
+func parseEMV(s string) ([]emvTag, error) {
+	nodes, err := parseEMVTree(s)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]emvTag, 0, len(nodes))
+	for _, n := range nodes {
+		tags = append(tags, emvTag{id: n.ID, value: n.Value})
+	}
 	return tags, nil
 }
 
+// findChecksumOffset walks s tag-by-tag from the start and returns the
+// byte offset where the terminal checksum tag "63" begins. Unlike a plain
+// substring search for "6304", this can't be fooled by an earlier field
+// (a merchant name, PAN, or reference label) whose value happens to
+// contain those four characters, since it only recognizes "63" when it
+// appears as an actual top-level tag header.
+func findChecksumOffset(s string) (int, error) {
+	i := 0
+	n := len(s)
+	for i < n {
+		if i+4 > n {
+			return -1, errors.New("malformed EMV data: incomplete tag header")
+		}
+		id := s[i : i+2]
+		lenStr := s[i+2 : i+4]
+		l, err := strconv.Atoi(lenStr)
+		if err != nil || l < 0 {
+			return -1, fmt.Errorf("invalid length for tag %s: %q is not a 2-digit unsigned length", id, lenStr)
+		}
+		if i+4+l > n {
+			return -1, fmt.Errorf("malformed EMV data: tag %s length out of range", id)
+		}
+		if id == "63" {
+			return i, nil
+		}
+		i = i + 4 + l
+	}
+	return -1, errors.New("missing checksum tag 63")
+}
+
 func rebuildEMVExcluding(tags []emvTag, excludeIDs map[string]bool) string {
 	var b strings.Builder
 	for _, t := range tags {
@@ -102,24 +210,40 @@ func insertTagBefore(tags []emvTag, beforeID string, newTag emvTag) []emvTag {
 	return res
 }
 
-func formatAmountTag(amountStr string) (string, error) {
-	// Expect amountStr to be integer string (e.g. "15000") OR decimal with dot "15000.00"
-	// Convert to plain integer string without decimals for QRIS examples used here.
-	// If user passes decimal like 12.50 => we will remove dot and preserve cents (not common in IDR).
+// formatAmountValue normalizes amountStr into the "major.minor" decimal
+// string EMVCo expects for tag 54 (e.g. "15000.00"). By default amountStr
+// is a major-unit amount, either a bare integer ("15000") or already
+// dotted ("15000.5"); either way the result always carries exactly two
+// decimal digits. When minorUnits is true, amountStr is instead a plain
+// integer count of minor units (cents) and is shifted down by 100.
+func formatAmountValue(amountStr string, minorUnits bool) (string, error) {
 	amountStr = strings.TrimSpace(amountStr)
 	if amountStr == "" {
 		return "", errors.New("empty amount")
 	}
-	// If contains dot, remove dot
+
+	if minorUnits {
+		if strings.Contains(amountStr, ".") {
+			return "", errors.New("amount must be a plain integer of minor units when currency_minor_units is set")
+		}
+		cents, err := strconv.ParseInt(amountStr, 10, 64)
+		if err != nil {
+			return "", errors.New("amount contains non-numeric characters")
+		}
+		sign := ""
+		if cents < 0 {
+			sign = "-"
+			cents = -cents
+		}
+		return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100), nil
+	}
+
 	if strings.Contains(amountStr, ".") {
-		// validate numeric
 		parts := strings.Split(amountStr, ".")
 		if len(parts) != 2 {
 			return "", errors.New("invalid amount format")
 		}
-		intPart := parts[0]
-		decPart := parts[1]
-		// normalize decimal to at most 2 digits
+		intPart, decPart := parts[0], parts[1]
 		if len(decPart) > 2 {
 			decPart = decPart[:2]
 		} else if len(decPart) == 1 {
@@ -127,20 +251,321 @@ func formatAmountTag(amountStr string) (string, error) {
 		} else if len(decPart) == 0 {
 			decPart = "00"
 		}
-		amt := intPart + decPart
-		if _, err := strconv.Atoi(amt); err != nil {
+		if _, err := strconv.Atoi(intPart); err != nil {
+			return "", errors.New("amount contains non-numeric characters")
+		}
+		if _, err := strconv.Atoi(decPart); err != nil {
 			return "", errors.New("amount contains non-numeric characters")
 		}
-		return fmt.Sprintf("54%02d%s", len(amt), amt), nil
+		return intPart + "." + decPart, nil
 	}
-	// no dot: treat as whole currency (IDR). tag value = digits of amount (e.g. 15000)
+
 	if _, err := strconv.Atoi(amountStr); err != nil {
 		return "", errors.New("amount contains non-numeric characters")
 	}
-	val := amountStr
+	return amountStr + ".00", nil
+}
+
+func formatAmountTag(amountStr string, minorUnits bool) (string, error) {
+	val, err := formatAmountValue(amountStr, minorUnits)
+	if err != nil {
+		return "", err
+	}
 	return fmt.Sprintf("54%02d%s", len(val), val), nil
 }
 
+// buildTag returns a single EMV TLV entry with the given id and value;
+// rebuildEMVExcluding renders it as "{id}{len:02d}{value}".
+func buildTag(id string, value string) emvTag {
+	return emvTag{id: id, value: value}
+}
+
+// insertByTagOrder inserts newTag among tags keeping ascending numeric tag
+// ID order, per EMVCo's field ordering requirement. Tags whose id doesn't
+// parse as a number (there shouldn't be any in a well-formed QRIS payload)
+// are left where they are and never displaced.
+func insertByTagOrder(tags []emvTag, newTag emvTag) []emvTag {
+	newID, err := strconv.Atoi(newTag.id)
+	if err != nil {
+		return append(tags, newTag)
+	}
+	res := make([]emvTag, 0, len(tags)+1)
+	inserted := false
+	for _, t := range tags {
+		if !inserted {
+			if tid, err := strconv.Atoi(t.id); err == nil && tid > newID {
+				res = append(res, newTag)
+				inserted = true
+			}
+		}
+		res = append(res, t)
+	}
+	if !inserted {
+		res = append(res, newTag)
+	}
+	return res
+}
+
+// additionalDataFields holds the optional tag 62 sub-fields, keyed by the
+// JSON names accepted in a request body.
+type additionalDataFields struct {
+	BillNumber           string `json:"bill_number"`
+	MobileNumber         string `json:"mobile_number"`
+	StoreLabel           string `json:"store_label"`
+	LoyaltyNumber        string `json:"loyalty_number"`
+	ReferenceLabel       string `json:"reference_label"`
+	CustomerLabel        string `json:"customer_label"`
+	TerminalLabel        string `json:"terminal_label"`
+	PurposeOfTransaction string `json:"purpose_of_transaction"`
+}
+
+// buildAdditionalDataTag assembles tag 62 from whichever sub-fields are
+// set, in EMVCo sub-tag order. ok is false when no sub-field was set, so
+// callers can skip inserting an empty tag 62.
+func buildAdditionalDataTag(ad additionalDataFields) (tag emvTag, ok bool) {
+	subFields := []struct {
+		id  string
+		val string
+	}{
+		{"01", ad.BillNumber},
+		{"02", ad.MobileNumber},
+		{"03", ad.StoreLabel},
+		{"04", ad.LoyaltyNumber},
+		{"05", ad.ReferenceLabel},
+		{"06", ad.CustomerLabel},
+		{"07", ad.TerminalLabel},
+		{"08", ad.PurposeOfTransaction},
+	}
+	var b strings.Builder
+	for _, f := range subFields {
+		if f.val == "" {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("%s%02d%s", f.id, len(f.val), f.val))
+	}
+	if b.Len() == 0 {
+		return emvTag{}, false
+	}
+	return emvTag{id: "62", value: b.String()}, true
+}
+
+// buildExpiryTag encodes an expiry timestamp expirySeconds from now into
+// unreserved template tag 80, sub-tag 01, as a Unix epoch string.
+func buildExpiryTag(expirySeconds int64) emvTag {
+	expiresAt := time.Now().Add(time.Duration(expirySeconds) * time.Second).Unix()
+	val := strconv.FormatInt(expiresAt, 10)
+	return emvTag{id: "80", value: fmt.Sprintf("01%02d%s", len(val), val)}
+}
+
+// amountInsertionPoint decides where a new tag 54 belongs: before the
+// country code (58) if present, otherwise before additional data (62) if
+// present, otherwise at the end (insertTagBefore appends when the target
+// id isn't found).
+func amountInsertionPoint(tags []emvTag) string {
+	for _, t := range tags {
+		if t.id == "58" {
+			return "58"
+		}
+	}
+	for _, t := range tags {
+		if t.id == "62" {
+			return "62"
+		}
+	}
+	return "62"
+}
+
+// batchItem is one entry of a /qris-statis-to-dinamis/batch request.
+type batchItem struct {
+	Amount         string `json:"amount"`
+	ReferenceLabel string `json:"reference_label"`
+	BillNumber     string `json:"bill_number"`
+	Size           int    `json:"size"`
+	ECC            string `json:"ecc"`
+}
+
+// batchResult is one entry of a /qris-statis-to-dinamis/batch response.
+// Error is set instead of the other fields when this item failed, so one
+// bad item doesn't fail the whole batch.
+type batchResult struct {
+	DinamisQRIS string `json:"dinamis_qris,omitempty"`
+	QRBase64    string `json:"qr_base64,omitempty"`
+	CRC         string `json:"crc,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// eccFromString maps the L/M/Q/H recovery level letters to go-qrcode's
+// RecoveryLevel, defaulting to Medium when s is empty.
+func eccFromString(s string) (qrcodeGen.RecoveryLevel, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "", "M":
+		return qrcodeGen.Medium, nil
+	case "L":
+		return qrcodeGen.Low, nil
+	case "Q":
+		return qrcodeGen.High, nil
+	case "H":
+		return qrcodeGen.Highest, nil
+	default:
+		return 0, fmt.Errorf("invalid ecc %q, expected one of L, M, Q, H", s)
+	}
+}
+
+// buildDinamisQRIS inserts item's amount into baseTags at insertBefore and,
+// if set, a tag 62 built from its reference label / bill number in ascending
+// tag order, then rebuilds the EMV string and appends a freshly computed
+// checksum.
+func buildDinamisQRIS(baseTags []emvTag, insertBefore string, item batchItem) (finalQR string, crc string, err error) {
+	amountTag, err := formatAmountTag(item.Amount, false)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid amount: %w", err)
+	}
+	new54 := emvTag{id: "54", value: amountTag[4:]}
+	finalTags := insertTagBefore(baseTags, insertBefore, new54)
+
+	if ad, ok := buildAdditionalDataTag(additionalDataFields{
+		BillNumber:     item.BillNumber,
+		ReferenceLabel: item.ReferenceLabel,
+	}); ok {
+		finalTags = insertByTagOrder(finalTags, ad)
+	}
+
+	newCore := rebuildEMVExcluding(finalTags, map[string]bool{})
+	crcInput := newCore + "6304"
+	crc = crcHexUpper4(crcInput)
+	return newCore + "6304" + crc, crc, nil
+}
+
+// renderBatchItem builds and renders one batch entry, converting any
+// failure into a batchResult.Error instead of propagating it, so the rest
+// of the batch keeps going.
+func renderBatchItem(baseTags []emvTag, insertBefore string, item batchItem) batchResult {
+	finalQR, crc, err := buildDinamisQRIS(baseTags, insertBefore, item)
+	if err != nil {
+		return batchResult{Error: err.Error()}
+	}
+	level, err := eccFromString(item.ECC)
+	if err != nil {
+		return batchResult{Error: err.Error()}
+	}
+	size := item.Size
+	if size <= 0 {
+		size = 256
+	}
+	b64, _, err := renderQR(finalQR, level, size, "png", 0, 0)
+	if err != nil {
+		return batchResult{Error: "failed to generate QR image: " + err.Error()}
+	}
+	return batchResult{DinamisQRIS: finalQR, QRBase64: b64, CRC: crc}
+}
+
+// batchWorkerCount reads BATCH_WORKERS, falling back to runtime.NumCPU()
+// when unset or invalid.
+func batchWorkerCount() int {
+	if v := os.Getenv("BATCH_WORKERS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return runtime.NumCPU()
+}
+
+// defaultModuleSize and defaultQuietZone are the SVG renderer's fallbacks
+// when the caller omits module_size/quiet_zone; defaultQuietZone matches
+// the EMVCo-recommended 4-module quiet zone. They don't need to track the
+// PNG path, which auto-scales modules to fit the requested pixel size.
+const (
+	defaultModuleSize = 8
+	defaultQuietZone  = 4
+)
+
+// bitmapToSVG renders a QR bitmap (true = dark module) as a single SVG
+// <path>, collapsing each horizontal run of dark modules into one
+// rectangular "M h v h z" subpath to keep the markup small.
+func bitmapToSVG(bitmap [][]bool, moduleSize int, quietZone int) string {
+	rows := len(bitmap)
+	cols := 0
+	if rows > 0 {
+		cols = len(bitmap[0])
+	}
+	width := (cols + 2*quietZone) * moduleSize
+	height := (rows + 2*quietZone) * moduleSize
+
+	var path strings.Builder
+	for y, row := range bitmap {
+		x := 0
+		for x < len(row) {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			runWidth := (x - start) * moduleSize
+			px := (start + quietZone) * moduleSize
+			py := (y + quietZone) * moduleSize
+			fmt.Fprintf(&path, "M%d %d h%d v%d h%d z", px, py, runWidth, moduleSize, -runWidth)
+		}
+	}
+
+	return fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d"><rect width="100%%" height="100%%" fill="#ffffff"/><path d="%s" fill="#000000"/></svg>`,
+		width, height, width, height, path.String(),
+	)
+}
+
+// renderQR encodes content as a QR code and returns the requested
+// representations. format is one of "png" (default), "svg", or "both";
+// moduleSize and quietZone only affect the svg output (0 falls back to
+// the defaults above). size is the PNG's pixel width.
+func renderQR(content string, level qrcodeGen.RecoveryLevel, size int, format string, moduleSize int, quietZone int) (pngB64 string, svg string, err error) {
+	if format == "" {
+		format = "png"
+	}
+	if moduleSize <= 0 {
+		moduleSize = defaultModuleSize
+	}
+	if quietZone <= 0 {
+		quietZone = defaultQuietZone
+	}
+
+	switch format {
+	case "png", "svg", "both":
+	default:
+		return "", "", fmt.Errorf("unsupported format %q", format)
+	}
+
+	if format == "png" {
+		png, err := qrcodeGen.Encode(content, level, size)
+		if err != nil {
+			return "", "", err
+		}
+		return base64.StdEncoding.EncodeToString(png), "", nil
+	}
+
+	code, err := qrcodeGen.New(content, level)
+	if err != nil {
+		return "", "", err
+	}
+	// Bitmap() bakes in go-qrcode's own 4-module quiet zone by default;
+	// disable it so bitmapToSVG works from a bare module matrix and owns
+	// the entire quiet zone via its own quietZone parameter.
+	code.DisableBorder = true
+
+	if format == "svg" {
+		return "", bitmapToSVG(code.Bitmap(), moduleSize, quietZone), nil
+	}
+
+	// format == "both"
+	png, err := qrcodeGen.Encode(content, level, size)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(png), bitmapToSVG(code.Bitmap(), moduleSize, quietZone), nil
+}
+
 func getEnv(key string, defaultValue string) string {
 	v := os.Getenv(key)
 	if v == "" {
@@ -164,8 +589,17 @@ func main() {
 
 	apiV1.Post("/qris-statis-to-dinamis", func(c *fiber.Ctx) error {
 		var body struct {
-			Amount     string `json:"amount"`
-			StaticQRIS string `json:"static_qris"`
+			Amount             string               `json:"amount"`
+			StaticQRIS         string               `json:"static_qris"`
+			Format             string               `json:"format"`
+			ModuleSize         int                  `json:"module_size"`
+			QuietZone          int                  `json:"quiet_zone"`
+			CurrencyMinorUnits bool                 `json:"currency_minor_units"`
+			TipIndicator       string               `json:"tip_indicator"`
+			FixedTip           string               `json:"fixed_tip"`
+			PercentageTip      string               `json:"percentage_tip"`
+			AdditionalData     additionalDataFields `json:"additional_data"`
+			ExpirySeconds      int64                `json:"expiry_seconds"`
 		}
 		if err := c.BodyParser(&body); err != nil {
 			log.Error().Err(err).Msg("Failed to parse body")
@@ -179,10 +613,10 @@ func main() {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "static_qris too short"})
 		}
 
-		// find checksum tag "6304" position
-		idx := strings.Index(static, "6304")
-		if idx == -1 {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "static_qris missing checksum tag 63"})
+		// find checksum tag "63" position
+		idx, err := findChecksumOffset(static)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "static_qris missing checksum tag 63: " + err.Error()})
 		}
 		core := static[:idx] // exclude existing 63 and checksum
 
@@ -194,7 +628,7 @@ func main() {
 		}
 
 		// build new amount tag
-		amountTag, err := formatAmountTag(amount)
+		amountTag, err := formatAmountTag(amount, body.CurrencyMinorUnits)
 		if err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid amount: " + err.Error()})
 		}
@@ -214,27 +648,26 @@ func main() {
 
 		// prepare new tag struct for 54
 		new54 := emvTag{id: "54", value: amountTag[4:]} // amountTag = "54" + len(2) + value; skip header
-		// decide insertion point
-		insertBefore := "58" // country code usually 58
-		found := false
-		for _, t := range rebuiltList {
-			if t.id == insertBefore {
-				found = true
-				break
-			}
+		// now insert
+		finalTags := insertTagBefore(rebuiltList, amountInsertionPoint(rebuiltList), new54)
+
+		// tip fields (55/56/57), additional data (62), and expiry (80) all
+		// follow EMVCo ascending tag order relative to what's already there
+		if body.TipIndicator != "" {
+			finalTags = insertByTagOrder(finalTags, buildTag("55", body.TipIndicator))
 		}
-		if !found {
-			insertBefore = "62"
-			found = false
-			for _, t := range rebuiltList {
-				if t.id == insertBefore {
-					found = true
-					break
-				}
-			}
+		if body.FixedTip != "" {
+			finalTags = insertByTagOrder(finalTags, buildTag("56", body.FixedTip))
+		}
+		if body.PercentageTip != "" {
+			finalTags = insertByTagOrder(finalTags, buildTag("57", body.PercentageTip))
+		}
+		if additionalDataTag, ok := buildAdditionalDataTag(body.AdditionalData); ok {
+			finalTags = insertByTagOrder(finalTags, additionalDataTag)
+		}
+		if body.ExpirySeconds > 0 {
+			finalTags = insertByTagOrder(finalTags, buildExpiryTag(body.ExpirySeconds))
 		}
-		// now insert
-		finalTags := insertTagBefore(rebuiltList, insertBefore, new54)
 
 		// rebuild EMV core (without checksum)
 		newCore := rebuildEMVExcluding(finalTags, map[string]bool{})
@@ -245,33 +678,102 @@ func main() {
 		finalQR := newCore + "6304" + crc
 
 		// generate QR image
-		png, err := qrcodeGen.Encode(finalQR, qrcodeGen.Medium, 256)
+		b64, svg, err := renderQR(finalQR, qrcodeGen.Medium, 256, body.Format, body.ModuleSize, body.QuietZone)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to generate QR image")
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to generate QR image"})
 		}
-		b64 := base64.StdEncoding.EncodeToString(png)
 
 		log.Info().Str("final_qr", finalQR).Msg("QRIS dinamis generated")
-		return c.JSON(fiber.Map{"dinamis_qris": finalQR, "qr_base64": b64})
+		resp := fiber.Map{"dinamis_qris": finalQR}
+		if b64 != "" {
+			resp["qr_base64"] = b64
+		}
+		if svg != "" {
+			resp["qr_svg"] = svg
+		}
+		return c.JSON(resp)
+	})
+
+	apiV1.Post("/qris-statis-to-dinamis/batch", func(c *fiber.Ctx) error {
+		var body struct {
+			StaticQRIS string      `json:"static_qris"`
+			Items      []batchItem `json:"items"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			log.Error().Err(err).Msg("Failed to parse body")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+
+		static := strings.TrimSpace(body.StaticQRIS)
+		if len(static) < 10 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "static_qris too short"})
+		}
+		if len(body.Items) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "items must not be empty"})
+		}
+
+		idx, err := findChecksumOffset(static)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "static_qris missing checksum tag 63: " + err.Error()})
+		}
+		core := static[:idx]
+
+		tags, err := parseEMV(core)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to parse EMV tags")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "failed to parse static_qris: " + err.Error()})
+		}
+		baseTags := make([]emvTag, 0, len(tags))
+		for _, t := range tags {
+			if t.id == "54" {
+				continue
+			}
+			baseTags = append(baseTags, t)
+		}
+		insertBefore := amountInsertionPoint(baseTags)
+
+		results := make([]batchResult, len(body.Items))
+		var g errgroup.Group
+		g.SetLimit(batchWorkerCount())
+		for i, item := range body.Items {
+			i, item := i, item
+			g.Go(func() error {
+				results[i] = renderBatchItem(baseTags, insertBefore, item)
+				return nil // never fail the group: a bad item is recorded in results[i].Error
+			})
+		}
+		g.Wait()
+
+		log.Info().Int("items", len(results)).Msg("Batch QRIS dinamis generated")
+		return c.JSON(fiber.Map{"results": results})
 	})
 
 	apiV1.Post("/string-to-qr", func(c *fiber.Ctx) error {
 		var body struct {
-			Text string `json:"text"`
+			Text       string `json:"text"`
+			Format     string `json:"format"`
+			ModuleSize int    `json:"module_size"`
+			QuietZone  int    `json:"quiet_zone"`
 		}
 		if err := c.BodyParser(&body); err != nil {
 			log.Error().Err(err).Msg("Failed to parse body")
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
 		}
-		png, err := qrcodeGen.Encode(body.Text, qrcodeGen.Medium, 256)
+		b64, svg, err := renderQR(body.Text, qrcodeGen.Medium, 256, body.Format, body.ModuleSize, body.QuietZone)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to generate QR")
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to generate QR"})
 		}
-		b64 := base64.StdEncoding.EncodeToString(png)
 		log.Info().Msg("QR generated from string")
-		return c.JSON(fiber.Map{"qr_base64": b64})
+		resp := fiber.Map{}
+		if b64 != "" {
+			resp["qr_base64"] = b64
+		}
+		if svg != "" {
+			resp["qr_svg"] = svg
+		}
+		return c.JSON(resp)
 	})
 
 	apiV1.Post("/qr-to-string", func(c *fiber.Ctx) error {
@@ -305,6 +807,173 @@ func main() {
 		return c.JSON(fiber.Map{"text": string(qrCodes[0].Payload)})
 	})
 
+	apiV1.Post("/qris-decode", func(c *fiber.Ctx) error {
+		var body struct {
+			QRIS string `json:"qris"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			log.Error().Err(err).Msg("Failed to parse body")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+
+		qris := strings.TrimSpace(body.QRIS)
+		if len(qris) < 10 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "qris too short"})
+		}
+
+		idx, err := findChecksumOffset(qris)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "qris missing checksum tag 63: " + err.Error()})
+		}
+		if idx+8 > len(qris) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "qris checksum tag 63 has incomplete value"})
+		}
+		core := qris[:idx]
+		actualCRC := strings.ToUpper(qris[idx+4 : idx+8])
+		expectedCRC := crcHexUpper4(core + "6304")
+
+		nodes, err := parseEMVTree(core)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to parse EMV tags")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "failed to parse qris: " + err.Error()})
+		}
+
+		mpmType := "unknown"
+		switch emvValue(nodes, "01") {
+		case "11":
+			mpmType = "static"
+		case "12":
+			mpmType = "dynamic"
+		}
+
+		var merchantAccount string
+		for _, n := range nodes {
+			id, err := strconv.Atoi(n.ID)
+			if err != nil || id < 26 || id > 51 {
+				continue
+			}
+			if sub, ok := findEMVNode(n.Children, "02"); ok {
+				merchantAccount = sub.Value
+				break
+			}
+		}
+
+		additionalData := map[string]string{}
+		if tag62, ok := findEMVNode(nodes, "62"); ok {
+			subFields := map[string]string{
+				"01": "bill_number",
+				"02": "mobile_number",
+				"03": "store_label",
+				"05": "reference_label",
+				"07": "terminal_label",
+				"08": "purpose_of_transaction",
+			}
+			for _, child := range tag62.Children {
+				if name, ok := subFields[child.ID]; ok {
+					additionalData[name] = child.Value
+				}
+			}
+		}
+
+		log.Info().Bool("crc_valid", actualCRC == expectedCRC).Msg("QRIS decoded")
+		return c.JSON(fiber.Map{
+			"mpm_type":               mpmType,
+			"merchant_account":       merchantAccount,
+			"merchant_category_code": emvValue(nodes, "52"),
+			"transaction_currency":   emvValue(nodes, "53"),
+			"amount":                 emvValue(nodes, "54"),
+			"country":                emvValue(nodes, "58"),
+			"merchant_name":          emvValue(nodes, "59"),
+			"city":                   emvValue(nodes, "60"),
+			"postal_code":            emvValue(nodes, "61"),
+			"additional_data":        additionalData,
+			"crc_valid":              actualCRC == expectedCRC,
+			"crc_expected":           expectedCRC,
+			"crc_actual":             actualCRC,
+			"tags":                   nodes,
+		})
+	})
+
+	apiV1.Post("/qris-multi-chunk", func(c *fiber.Ctx) error {
+		var body struct {
+			Text      string `json:"text"`
+			ChunkSize int    `json:"chunk_size"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			log.Error().Err(err).Msg("Failed to parse body")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+		if strings.TrimSpace(body.Text) == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "text must not be empty"})
+		}
+
+		frames, err := qr.Split(body.Text, body.ChunkSize)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to split text into chunks")
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to split text into chunks"})
+		}
+
+		chunks := make([]string, 0, len(frames))
+		for _, f := range frames {
+			png, err := qrcodeGen.Encode(f.Encode(), qrcodeGen.Medium, 256)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to generate QR chunk image")
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to generate QR chunk image"})
+			}
+			chunks = append(chunks, base64.StdEncoding.EncodeToString(png))
+		}
+
+		log.Info().Str("session_id", frames[0].SessionID).Int("total", len(frames)).Msg("Multi-chunk QR generated")
+		return c.JSON(fiber.Map{"session_id": frames[0].SessionID, "total": len(frames), "chunks": chunks})
+	})
+
+	apiV1.Post("/qr-multi-chunk-to-string", func(c *fiber.Ctx) error {
+		var body struct {
+			Images []string `json:"images"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			log.Error().Err(err).Msg("Failed to parse body")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+		if len(body.Images) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "images must not be empty"})
+		}
+
+		frames := make([]qr.Frame, 0, len(body.Images))
+		for i, b64 := range body.Images {
+			imgBytes, err := base64.StdEncoding.DecodeString(b64)
+			if err != nil {
+				log.Error().Err(err).Int("image", i).Msg("Failed to decode base64")
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("invalid base64 for image %d", i)})
+			}
+			img, _, err := image.Decode(bytes.NewReader(imgBytes))
+			if err != nil {
+				log.Error().Err(err).Int("image", i).Msg("Failed to decode image")
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("invalid image format for image %d", i)})
+			}
+			qrCodes, err := goqr.Recognize(img)
+			if err != nil || len(qrCodes) == 0 {
+				log.Error().Err(err).Int("image", i).Msg("Failed to decode QR (goqr)")
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("no QR code found in image %d", i)})
+			}
+			frame, err := qr.ParseFrame(string(qrCodes[0].Payload))
+			if err != nil {
+				log.Error().Err(err).Int("image", i).Msg("Failed to parse chunk frame")
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("image %d is not a chunk frame: %s", i, err.Error())})
+			}
+			frames = append(frames, frame)
+		}
+
+		text, err := qr.Reassemble(frames)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to reassemble chunks")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "failed to reassemble chunks: " + err.Error()})
+		}
+
+		log.Info().Str("session_id", frames[0].SessionID).Msg("Multi-chunk QR reassembled")
+		return c.JSON(fiber.Map{"text": text})
+	})
+
 	log.Info().Msg(fmt.Sprintf("Starting Fiber server on :%s", appPort))
 	app.Listen(fmt.Sprintf(":%s", appPort))
 }
@@ -0,0 +1,92 @@
+package main
+
+import "testing"
+
+func TestComputeCRC16CCITT(t *testing.T) {
+	// Standard CRC-16/CCITT-FALSE check value for the ASCII string
+	// "123456789" is 0x29B1 (poly 0x1021, init 0xFFFF, no reflect, xorout 0).
+	got := computeCRC16CCITT([]byte("123456789"))
+	if want := uint16(0x29B1); got != want {
+		t.Fatalf("computeCRC16CCITT(\"123456789\") = %04X, want %04X", got, want)
+	}
+}
+
+func TestCrcHexUpper4(t *testing.T) {
+	got := crcHexUpper4("123456789")
+	if want := "29B1"; got != want {
+		t.Fatalf("crcHexUpper4(\"123456789\") = %q, want %q", got, want)
+	}
+}
+
+func TestParseEMVTreeFlatTag(t *testing.T) {
+	nodes, err := parseEMVTree("5905HELLO")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodes) != 1 {
+		t.Fatalf("got %d nodes, want 1", len(nodes))
+	}
+	n := nodes[0]
+	if n.ID != "59" || n.Length != 5 || n.Value != "HELLO" || n.Children != nil {
+		t.Fatalf("got %+v, want {ID:59 Length:5 Value:HELLO Children:nil}", n)
+	}
+}
+
+func TestParseEMVTreeRecursesIntoTemplateTags(t *testing.T) {
+	// tag 62 (additional data) wrapping sub-tag 01 (bill number, "A12")
+	// and sub-tag 05 (reference label, "REF1").
+	s := "62150103A120504REF1"
+	nodes, err := parseEMVTree(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].ID != "62" {
+		t.Fatalf("got %+v, want single top-level node 62", nodes)
+	}
+	children := nodes[0].Children
+	if len(children) != 2 {
+		t.Fatalf("got %d children, want 2", len(children))
+	}
+	if children[0].ID != "01" || children[0].Value != "A12" {
+		t.Fatalf("child[0] = %+v, want {ID:01 Value:A12}", children[0])
+	}
+	if children[1].ID != "05" || children[1].Value != "REF1" {
+		t.Fatalf("child[1] = %+v, want {ID:05 Value:REF1}", children[1])
+	}
+}
+
+func TestParseEMVTreeRejectsNegativeLength(t *testing.T) {
+	if _, err := parseEMVTree("00-1"); err == nil {
+		t.Fatal("expected error for negative length field, got nil")
+	}
+}
+
+func TestParseEMVTreeRejectsTruncatedValue(t *testing.T) {
+	// tag 59 declares a 10-byte value but only 3 bytes follow.
+	if _, err := parseEMVTree("5910ABC"); err == nil {
+		t.Fatal("expected error for out-of-range length, got nil")
+	}
+}
+
+func TestFindChecksumOffsetIgnoresValueCollision(t *testing.T) {
+	// Merchant name (tag 59) whose value contains the literal "6304",
+	// followed by the real checksum tag 63. A substring search for
+	// "6304" would stop inside the merchant name instead of at the
+	// actual tag-63 header.
+	core := "5908AB6304CD"
+	full := core + "6304ABCD"
+
+	got, err := findChecksumOffset(full)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := len(core); got != want {
+		t.Fatalf("findChecksumOffset = %d, want %d (start of the real tag 63)", got, want)
+	}
+}
+
+func TestFindChecksumOffsetMissingTag(t *testing.T) {
+	if _, err := findChecksumOffset("5905HELLO"); err == nil {
+		t.Fatal("expected error when tag 63 is absent, got nil")
+	}
+}
@@ -0,0 +1,131 @@
+// Package qr implements the framing scheme used to split an oversized
+// payload across multiple QR codes and reassemble it again on the other
+// side, independent of the order frames are scanned in.
+package qr
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultChunkSize is the default number of payload bytes carried per frame
+// when the caller does not specify one.
+const DefaultChunkSize = 512
+
+// Frame is a single piece of a chunked payload. Encoded as text it looks
+// like "{sessionID}:{index}:{total}:{payload}" so a reader can reassemble
+// frames scanned in any order and detect missing pieces.
+type Frame struct {
+	SessionID string
+	Index     int
+	Total     int
+	Payload   string
+}
+
+// NewSessionID returns a short random hex identifier shared by every frame
+// of one chunking session.
+func NewSessionID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate session id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Encode renders the frame using the "{sessionID}:{index}:{total}:{payload}" format.
+func (f Frame) Encode() string {
+	return fmt.Sprintf("%s:%d:%d:%s", f.SessionID, f.Index, f.Total, f.Payload)
+}
+
+// ParseFrame parses a frame previously produced by Frame.Encode.
+func ParseFrame(s string) (Frame, error) {
+	parts := strings.SplitN(s, ":", 4)
+	if len(parts) != 4 {
+		return Frame{}, errors.New("malformed chunk frame: expected 4 colon-separated fields")
+	}
+	index, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Frame{}, fmt.Errorf("malformed chunk frame: invalid index: %w", err)
+	}
+	total, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return Frame{}, fmt.Errorf("malformed chunk frame: invalid total: %w", err)
+	}
+	if total <= 0 || index < 0 || index >= total {
+		return Frame{}, fmt.Errorf("malformed chunk frame: index %d out of range for total %d", index, total)
+	}
+	return Frame{SessionID: parts[0], Index: index, Total: total, Payload: parts[3]}, nil
+}
+
+// Split splits text into ordered frames of at most chunkSize payload bytes
+// each, all sharing a freshly generated session ID.
+func Split(text string, chunkSize int) ([]Frame, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	sessionID, err := NewSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	data := []byte(text)
+	total := (len(data) + chunkSize - 1) / chunkSize
+	if total == 0 {
+		total = 1
+	}
+
+	frames := make([]Frame, 0, total)
+	for i := 0; i < total; i++ {
+		start := i * chunkSize
+		end := start + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		frames = append(frames, Frame{
+			SessionID: sessionID,
+			Index:     i,
+			Total:     total,
+			Payload:   string(data[start:end]),
+		})
+	}
+	return frames, nil
+}
+
+// Reassemble validates that every frame belongs to the same session and
+// that all indices 0..total-1 are present exactly once, then concatenates
+// the payloads in index order.
+func Reassemble(frames []Frame) (string, error) {
+	if len(frames) == 0 {
+		return "", errors.New("no frames provided")
+	}
+
+	sessionID := frames[0].SessionID
+	total := frames[0].Total
+	seen := make(map[int]string, total)
+	for _, f := range frames {
+		if f.SessionID != sessionID {
+			return "", fmt.Errorf("frame session id %q does not match %q", f.SessionID, sessionID)
+		}
+		if f.Total != total {
+			return "", fmt.Errorf("frame total %d does not match %d", f.Total, total)
+		}
+		if _, dup := seen[f.Index]; dup {
+			return "", fmt.Errorf("duplicate frame index %d", f.Index)
+		}
+		seen[f.Index] = f.Payload
+	}
+
+	var b strings.Builder
+	for i := 0; i < total; i++ {
+		payload, ok := seen[i]
+		if !ok {
+			return "", fmt.Errorf("missing frame index %d of %d", i, total)
+		}
+		b.WriteString(payload)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,89 @@
+package qr
+
+import "testing"
+
+func TestSplitReassembleRoundTrip(t *testing.T) {
+	text := "the quick brown fox jumps over the lazy dog, repeated for length, "
+	long := ""
+	for i := 0; i < 20; i++ {
+		long += text
+	}
+
+	frames, err := Split(long, 64)
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	if len(frames) < 2 {
+		t.Fatalf("got %d frames, want more than 1 for this input", len(frames))
+	}
+
+	got, err := Reassemble(frames)
+	if err != nil {
+		t.Fatalf("Reassemble: %v", err)
+	}
+	if got != long {
+		t.Fatalf("round trip mismatch: got %d bytes, want %d bytes", len(got), len(long))
+	}
+}
+
+func TestSplitReassembleOutOfOrder(t *testing.T) {
+	frames, err := Split("reassembly should not depend on scan order", 8)
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+
+	// Reverse the frame order to simulate frames scanned out of sequence.
+	shuffled := make([]Frame, len(frames))
+	for i, f := range frames {
+		shuffled[len(frames)-1-i] = f
+	}
+
+	got, err := Reassemble(shuffled)
+	if err != nil {
+		t.Fatalf("Reassemble: %v", err)
+	}
+	if want := "reassembly should not depend on scan order"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestReassembleDetectsMissingFrame(t *testing.T) {
+	frames, err := Split("some payload long enough to span multiple chunks", 8)
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	if len(frames) < 2 {
+		t.Fatalf("need at least 2 frames for this test, got %d", len(frames))
+	}
+
+	if _, err := Reassemble(frames[1:]); err == nil {
+		t.Fatal("expected error for missing frame 0, got nil")
+	}
+}
+
+func TestReassembleRejectsMixedSessions(t *testing.T) {
+	a, err := Split("payload a", 4)
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	b, err := Split("payload b", 4)
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+
+	mixed := append(append([]Frame{}, a...), b...)
+	if _, err := Reassemble(mixed); err == nil {
+		t.Fatal("expected error for mismatched session ids, got nil")
+	}
+}
+
+func TestParseFrameRoundTrip(t *testing.T) {
+	f := Frame{SessionID: "abc123", Index: 2, Total: 5, Payload: "hello:world"}
+	parsed, err := ParseFrame(f.Encode())
+	if err != nil {
+		t.Fatalf("ParseFrame: %v", err)
+	}
+	if parsed != f {
+		t.Fatalf("got %+v, want %+v", parsed, f)
+	}
+}